@@ -0,0 +1,464 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// vfsHeaderSize = Magic (4) + Version (4) + FileCount (4)
+	vfsHeaderSize = 12
+	// entryFixedMetadataSuffixSize = FileSize (4) + FileOffset (4) + Unknown/Padding (8)
+	// Size of the fixed metadata part *after* the filename.
+	// Based on the original seek logic (pos_before_size_offset + 16),
+	// where pos_before_size_offset is the position *before* reading fileSize (4 bytes) and fileOffset (4 bytes),
+	// the total offset was 16 bytes. This means that after fileOffset (4 bytes), there are another 16 - 4 - 4 = 8 bytes
+	// before the start of the next entry (name length).
+	entryFixedMetadataSuffixSize = 16
+)
+
+// vfsMagicBytes - Expected magic bytes for Pathologic VFS files.
+var vfsMagicBytes = []byte("LP1C")
+
+// supportedVFSVersion - The VFS format version supported by this unpacker.
+var supportedVFSVersion = []byte{0, 0, 0, 0}
+
+// VFSHeader represents the header of a VFS archive.
+type VFSHeader struct {
+	Magic     [4]byte
+	Version   [4]byte
+	FileCount uint32
+}
+
+// VFSEntryMetadata represents the metadata for a single file within the VFS archive.
+type VFSEntryMetadata struct {
+	// Name is the entry's path inside the archive, using forward slashes
+	// regardless of the separator convention stored on disk.
+	Name       string
+	FileSize   uint32
+	FileOffset uint32
+
+	// padding holds the 8 bytes stored after FileOffset whose purpose is
+	// unknown; Inspect reports whether it's all zero or something else.
+	padding [8]byte
+}
+
+// Archive is a read-only, random-access view over a VFS file. Unlike
+// Unpacker, it parses every entry's metadata up front and never reads file
+// data until a caller actually opens that file, so it can be mounted as an
+// fs.FS or http.FileSystem without extracting anything to disk.
+type Archive struct {
+	vfsFile *os.File
+	vfsSize int64
+
+	// Header is the parsed VFS header.
+	Header VFSHeader
+
+	entries     []VFSEntryMetadata
+	byName      map[string]*VFSEntryMetadata
+	dirChildren map[string][]fs.DirEntry
+}
+
+// NewArchive opens vfsPath, verifies its header, and parses the full table
+// of contents into memory. Any entry with an invalid name, an out-of-range
+// data offset/size, or a path that collides with another entry is rejected,
+// since those are invariants the fs.FS/http.FileSystem views and extraction
+// both depend on. Use NewArchiveForInspect to look inside a file that fails
+// these checks.
+func NewArchive(vfsPath string) (*Archive, error) {
+	f, header, vfsSize, err := openAndReadHeader(vfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseEntries(f, header.FileCount, vfsSize, true)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	byName, dirChildren, err := buildEntryTree(entries)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Archive{
+		vfsFile:     f,
+		vfsSize:     vfsSize,
+		Header:      header,
+		entries:     entries,
+		byName:      byName,
+		dirChildren: dirChildren,
+	}, nil
+}
+
+// NewArchiveForInspect opens vfsPath like NewArchive, but tolerates the
+// structural problems NewArchive treats as fatal (empty names, out-of-range
+// data offsets/sizes) so that buildInspectReport can surface them as
+// reported problems instead of the file failing to open at all. The
+// resulting Archive's Entries/Size/Header work as usual, but it is not
+// indexed for fs.FS/http.FileSystem use - inspect doesn't need that.
+func NewArchiveForInspect(vfsPath string) (*Archive, error) {
+	f, header, vfsSize, err := openAndReadHeader(vfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseEntries(f, header.FileCount, vfsSize, false)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Archive{
+		vfsFile: f,
+		vfsSize: vfsSize,
+		Header:  header,
+		entries: entries,
+	}, nil
+}
+
+// openAndReadHeader opens vfsPath and reads and verifies its header,
+// returning the open file positioned right after the header along with the
+// parsed header and the file's total size.
+func openAndReadHeader(vfsPath string) (*os.File, VFSHeader, int64, error) {
+	f, err := os.Open(vfsPath)
+	if err != nil {
+		return nil, VFSHeader{}, 0, fmt.Errorf("failed to open VFS file '%s': %w", vfsPath, err)
+	}
+
+	vfsInfo, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, VFSHeader{}, 0, fmt.Errorf("failed to get VFS file info: %w", err)
+	}
+	vfsSize := vfsInfo.Size()
+
+	if vfsSize < vfsHeaderSize {
+		f.Close()
+		return nil, VFSHeader{}, 0, fmt.Errorf("invalid VFS file: size (%d bytes) is too small (minimum %d)", vfsSize, vfsHeaderSize)
+	}
+
+	header, err := readAndVerifyHeader(f)
+	if err != nil {
+		f.Close()
+		return nil, VFSHeader{}, 0, err
+	}
+
+	return f, header, vfsSize, nil
+}
+
+// Size returns the total size of the underlying VFS file in bytes.
+func (a *Archive) Size() int64 {
+	return a.vfsSize
+}
+
+// Close closes the underlying VFS file.
+func (a *Archive) Close() error {
+	if a.vfsFile != nil {
+		return a.vfsFile.Close()
+	}
+	return nil
+}
+
+// Entries returns a copy of the archive's table of contents, in on-disk
+// order.
+func (a *Archive) Entries() []VFSEntryMetadata {
+	out := make([]VFSEntryMetadata, len(a.entries))
+	copy(out, a.entries)
+	return out
+}
+
+// HTTPFileSystem adapts the archive to http.FileSystem, suitable for
+// http.FileServer.
+func (a *Archive) HTTPFileSystem() http.FileSystem {
+	return http.FS(a)
+}
+
+// Open implements fs.FS. Directories are served from the in-memory entry
+// tree; file contents are streamed lazily via an io.SectionReader over the
+// underlying archive file, so opening a file never reads its data into
+// memory and concurrent opens of disjoint files are safe.
+func (a *Archive) Open(name string) (fs.File, error) {
+	if name == "." {
+		return &archiveDirHandle{name: ".", children: a.dirChildren["."]}, nil
+	}
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if entry, ok := a.byName[name]; ok {
+		sr := io.NewSectionReader(a.vfsFile, int64(entry.FileOffset), int64(entry.FileSize))
+		return &archiveFile{SectionReader: sr, entry: entry}, nil
+	}
+	if children, ok := a.dirChildren[name]; ok {
+		return &archiveDirHandle{name: name, children: children}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (a *Archive) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := a.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return dir.ReadDir(-1)
+}
+
+// readAndVerifyHeader reads and verifies the VFS file header.
+func readAndVerifyHeader(f *os.File) (VFSHeader, error) {
+	var header VFSHeader
+
+	magic := make([]byte, len(vfsMagicBytes))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return header, fmt.Errorf("failed to read magic bytes: %w", err)
+	}
+	if !bytes.Equal(magic, vfsMagicBytes) {
+		return header, fmt.Errorf("invalid magic bytes: got '%s', expected '%s'. Is this a Pathologic VFS file?", string(magic), string(vfsMagicBytes))
+	}
+	copy(header.Magic[:], magic)
+
+	versionBytes := make([]byte, len(supportedVFSVersion))
+	if _, err := io.ReadFull(f, versionBytes); err != nil {
+		currentPos, _ := f.Seek(0, io.SeekCurrent)
+		return header, fmt.Errorf("failed to read version bytes (offset %d): %w", currentPos-int64(len(versionBytes)), err)
+	}
+	if !bytes.Equal(versionBytes, supportedVFSVersion) {
+		return header, fmt.Errorf("unsupported VFS format version: got %v, expected %v", versionBytes, supportedVFSVersion)
+	}
+	copy(header.Version[:], versionBytes)
+
+	if err := binary.Read(f, binary.LittleEndian, &header.FileCount); err != nil {
+		currentPos, _ := f.Seek(0, io.SeekCurrent)
+		return header, fmt.Errorf("failed to read file count (offset %d): %w", currentPos-4, err)
+	}
+
+	return header, nil
+}
+
+// parseEntries reads the full table of contents (but no file data)
+// starting right after the header. In strict mode (used by NewArchive) an
+// empty name or an out-of-range data offset/size aborts parsing entirely;
+// in non-strict mode (used by NewArchiveForInspect) the entry is kept as-is
+// so buildInspectReport can report the problem instead.
+func parseEntries(f *os.File, count uint32, vfsSize int64, strict bool) ([]VFSEntryMetadata, error) {
+	entries := make([]VFSEntryMetadata, 0, count)
+	if count == 0 {
+		return entries, nil
+	}
+
+	currentOffset, err := f.Seek(vfsHeaderSize, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seek to the start of file entries (offset %d): %w", vfsHeaderSize, err)
+	}
+
+	for i := uint32(0); i < count; i++ {
+		entryStartOffset := currentOffset
+
+		var nameLength uint8
+		if err := binary.Read(f, binary.LittleEndian, &nameLength); err != nil {
+			return nil, fmt.Errorf("entry %d (offset %d): failed to read name length: %w", i+1, entryStartOffset, err)
+		}
+		currentOffset++
+
+		if nameLength == 0 && strict {
+			return nil, fmt.Errorf("entry %d (offset %d): invalid name length (0)", i+1, entryStartOffset)
+		}
+
+		nameBytes := make([]byte, nameLength)
+		nRead, err := io.ReadFull(f, nameBytes)
+		currentOffset += int64(nRead)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d (offset %d): failed to read name (%d bytes): %w", i+1, entryStartOffset, nameLength, err)
+		}
+		// Normalize to the forward-slash form fs.FS requires, regardless
+		// of the separator convention the archive was written with.
+		name := strings.ReplaceAll(string(nameBytes), "\\", "/")
+
+		var fileSize, fileOffset uint32
+		if err := binary.Read(f, binary.LittleEndian, &fileSize); err != nil {
+			return nil, fmt.Errorf("entry %d ('%s'): failed to read file size (offset %d): %w", i+1, name, currentOffset, err)
+		}
+		currentOffset += 4
+
+		if err := binary.Read(f, binary.LittleEndian, &fileOffset); err != nil {
+			return nil, fmt.Errorf("entry %d ('%s'): failed to read file offset (offset %d): %w", i+1, name, currentOffset, err)
+		}
+		currentOffset += 4
+
+		if strict {
+			if int64(fileOffset) > vfsSize {
+				return nil, fmt.Errorf("entry %d ('%s'): invalid data offset %d (0x%X) - exceeds archive size %d", i+1, name, fileOffset, fileOffset, vfsSize)
+			}
+			if uint64(fileOffset)+uint64(fileSize) > uint64(vfsSize) {
+				return nil, fmt.Errorf("entry %d ('%s'): invalid data range - offset %d + size %d (%d) exceeds archive size %d", i+1, name, fileOffset, fileSize, uint64(fileOffset)+uint64(fileSize), vfsSize)
+			}
+		}
+
+		var padding [entryFixedMetadataSuffixSize - 4 - 4]byte
+		nPad, err := io.ReadFull(f, padding[:])
+		currentOffset += int64(nPad)
+		if err != nil {
+			if (err == io.EOF || err == io.ErrUnexpectedEOF) && i == count-1 {
+				// The metadata table for the last entry can be
+				// truncated at EOF; the file itself is still
+				// intact and addressable, so keep the entry
+				// rather than silently dropping it.
+				entries = append(entries, VFSEntryMetadata{Name: name, FileSize: fileSize, FileOffset: fileOffset, padding: padding})
+				break
+			}
+			return nil, fmt.Errorf("entry %d ('%s'): failed to read %d bytes of padding (current offset %d): %w", i+1, name, len(padding), currentOffset, err)
+		}
+
+		entries = append(entries, VFSEntryMetadata{Name: name, FileSize: fileSize, FileOffset: fileOffset, padding: padding})
+	}
+
+	return entries, nil
+}
+
+// buildEntryTree indexes entries by full path and synthesizes the
+// directory listings fs.ReadDirFS needs, since the archive itself only
+// stores a flat list of files.
+func buildEntryTree(entries []VFSEntryMetadata) (map[string]*VFSEntryMetadata, map[string][]fs.DirEntry, error) {
+	byName := make(map[string]*VFSEntryMetadata, len(entries))
+	children := make(map[string]map[string]fs.DirEntry)
+
+	ensureDir := func(dir string) map[string]fs.DirEntry {
+		set, ok := children[dir]
+		if !ok {
+			set = make(map[string]fs.DirEntry)
+			children[dir] = set
+		}
+		return set
+	}
+	ensureDir(".")
+
+	for i := range entries {
+		entry := &entries[i]
+		if !fs.ValidPath(entry.Name) {
+			return nil, nil, fmt.Errorf("entry %d: invalid archive path %q", i+1, entry.Name)
+		}
+		if _, exists := byName[entry.Name]; exists {
+			return nil, nil, fmt.Errorf("entry %d: duplicate archive path %q", i+1, entry.Name)
+		}
+		byName[entry.Name] = entry
+
+		ensureDir(path.Dir(entry.Name))[path.Base(entry.Name)] = fileDirEntry{entry}
+
+		for dir := path.Dir(entry.Name); dir != "."; {
+			parent := path.Dir(dir)
+			ensureDir(parent)[path.Base(dir)] = dirDirEntry{path.Base(dir)}
+			dir = parent
+		}
+	}
+
+	dirChildren := make(map[string][]fs.DirEntry, len(children))
+	for dir, set := range children {
+		list := make([]fs.DirEntry, 0, len(set))
+		for _, de := range set {
+			list = append(list, de)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+		dirChildren[dir] = list
+	}
+
+	return byName, dirChildren, nil
+}
+
+// archiveFile is an open handle to a single file's data, read lazily
+// straight from the underlying archive via an io.SectionReader.
+type archiveFile struct {
+	*io.SectionReader
+	entry *VFSEntryMetadata
+}
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) { return archiveFileInfo{f.entry}, nil }
+func (f *archiveFile) Close() error               { return nil }
+
+// archiveDirHandle is an open handle to a synthesized directory.
+type archiveDirHandle struct {
+	name     string
+	children []fs.DirEntry
+	offset   int
+}
+
+func (d *archiveDirHandle) Stat() (fs.FileInfo, error) { return archiveDirInfo{path.Base(d.name)}, nil }
+
+func (d *archiveDirHandle) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *archiveDirHandle) Close() error { return nil }
+
+func (d *archiveDirHandle) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		rest := d.children[d.offset:]
+		d.offset = len(d.children)
+		return rest, nil
+	}
+	if d.offset >= len(d.children) {
+		return nil, io.EOF
+	}
+	end := d.offset + n
+	if end > len(d.children) {
+		end = len(d.children)
+	}
+	batch := d.children[d.offset:end]
+	d.offset = end
+	return batch, nil
+}
+
+// fileDirEntry and dirDirEntry implement fs.DirEntry for, respectively, a
+// file backed by a VFSEntryMetadata and a synthesized directory.
+type fileDirEntry struct{ entry *VFSEntryMetadata }
+
+func (d fileDirEntry) Name() string               { return path.Base(d.entry.Name) }
+func (d fileDirEntry) IsDir() bool                { return false }
+func (d fileDirEntry) Type() fs.FileMode          { return 0 }
+func (d fileDirEntry) Info() (fs.FileInfo, error) { return archiveFileInfo{d.entry}, nil }
+
+type dirDirEntry struct{ name string }
+
+func (d dirDirEntry) Name() string               { return d.name }
+func (d dirDirEntry) IsDir() bool                { return true }
+func (d dirDirEntry) Type() fs.FileMode          { return fs.ModeDir }
+func (d dirDirEntry) Info() (fs.FileInfo, error) { return archiveDirInfo{d.name}, nil }
+
+// archiveFileInfo and archiveDirInfo implement fs.FileInfo for files and
+// synthesized directories. The archive format has no timestamps, so
+// ModTime is always the zero time.
+type archiveFileInfo struct{ entry *VFSEntryMetadata }
+
+func (fi archiveFileInfo) Name() string       { return path.Base(fi.entry.Name) }
+func (fi archiveFileInfo) Size() int64        { return int64(fi.entry.FileSize) }
+func (fi archiveFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi archiveFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi archiveFileInfo) IsDir() bool        { return false }
+func (fi archiveFileInfo) Sys() any           { return nil }
+
+type archiveDirInfo struct{ name string }
+
+func (di archiveDirInfo) Name() string       { return di.name }
+func (di archiveDirInfo) Size() int64        { return 0 }
+func (di archiveDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (di archiveDirInfo) ModTime() time.Time { return time.Time{} }
+func (di archiveDirInfo) IsDir() bool        { return true }
+func (di archiveDirInfo) Sys() any           { return nil }
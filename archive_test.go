@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// buildSampleArchive packs buildSampleTree's fixture and opens it as an
+// Archive for the fs.FS/http.FileSystem tests below.
+func buildSampleArchive(t *testing.T) (*Archive, map[string][]byte) {
+	t.Helper()
+
+	srcDir := t.TempDir()
+	original := buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	archive, err := NewArchive(archivePath)
+	if err != nil {
+		t.Fatalf("NewArchive failed: %v", err)
+	}
+	t.Cleanup(func() { archive.Close() })
+
+	return archive, original
+}
+
+func TestArchiveFSConformance(t *testing.T) {
+	archive, _ := buildSampleArchive(t)
+
+	if err := fstest.TestFS(archive, "readme.txt", "a/b.dat", "a/c/d.bin"); err != nil {
+		t.Fatalf("fstest.TestFS failed: %v", err)
+	}
+}
+
+func TestArchiveOpenReadsFileContents(t *testing.T) {
+	archive, original := buildSampleArchive(t)
+
+	for rel, want := range original {
+		name := filepath.ToSlash(rel)
+		f, err := archive.Open(name)
+		if err != nil {
+			t.Fatalf("Open(%q) failed: %v", name, err)
+		}
+		got, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatalf("reading %q failed: %v", name, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("closing %q failed: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("Open(%q) contents = %d bytes, want %d bytes", name, len(got), len(want))
+		}
+	}
+}
+
+func TestArchiveReadDirListsSyntheticDirectories(t *testing.T) {
+	archive, _ := buildSampleArchive(t)
+
+	entries, err := archive.ReadDir("a")
+	if err != nil {
+		t.Fatalf("ReadDir(\"a\") failed: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	want := map[string]bool{"b.dat": false, "c": true}
+	if len(names) != len(want) {
+		t.Fatalf("ReadDir(\"a\") returned %v, want entries for %v", names, want)
+	}
+	for _, e := range entries {
+		wantDir, ok := want[e.Name()]
+		if !ok {
+			t.Fatalf("ReadDir(\"a\") returned unexpected entry %q", e.Name())
+		}
+		if e.IsDir() != wantDir {
+			t.Fatalf("entry %q: IsDir() = %v, want %v", e.Name(), e.IsDir(), wantDir)
+		}
+	}
+}
+
+func TestArchiveOpenUnknownPath(t *testing.T) {
+	archive, _ := buildSampleArchive(t)
+
+	if _, err := archive.Open("does/not/exist"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Open of missing path: got err %v, want fs.ErrNotExist", err)
+	}
+}
+
+// TestArchiveParseEntriesKeepsLastEntryWithTruncatedPadding covers the case
+// where the final entry's 8-byte padding suffix is cut short by EOF: the
+// entry's name/size/offset were fully read and its data is addressable, so
+// it must still show up in Entries() rather than being silently dropped.
+func TestArchiveParseEntriesKeepsLastEntryWithTruncatedPadding(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(vfsMagicBytes)
+	buf.Write(supportedVFSVersion)
+	binary.Write(&buf, binary.LittleEndian, uint32(1))
+
+	name := "readme.txt"
+	buf.WriteByte(byte(len(name)))
+	buf.WriteString(name)
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // fileSize
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // fileOffset
+	buf.Write(make([]byte, 4))                         // only half of the 8-byte padding
+
+	archivePath := filepath.Join(t.TempDir(), "truncated.vfs")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	archive, err := NewArchive(archivePath)
+	if err != nil {
+		t.Fatalf("NewArchive failed: %v", err)
+	}
+	defer archive.Close()
+
+	entries := archive.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Entries() returned %d entries, want 1", len(entries))
+	}
+	if entries[0].Name != name {
+		t.Fatalf("Entries()[0].Name = %q, want %q", entries[0].Name, name)
+	}
+}
+
+func TestArchiveHTTPFileSystemServesFile(t *testing.T) {
+	archive, original := buildSampleArchive(t)
+
+	httpFS := archive.HTTPFileSystem()
+	f, err := httpFS.Open("/readme.txt")
+	if err != nil {
+		t.Fatalf("http.FileSystem.Open failed: %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading via http.FileSystem failed: %v", err)
+	}
+	if string(got) != string(original["readme.txt"]) {
+		t.Fatalf("http.FileSystem contents = %q, want %q", got, original["readme.txt"])
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// EntryReport is one row of an InspectReport: an entry's metadata plus the
+// derived fields the inspect/list CLI command prints.
+type EntryReport struct {
+	Name       string  `json:"name"`
+	FileOffset uint32  `json:"file_offset"`
+	FileSize   uint32  `json:"file_size"`
+	Percent    float64 `json:"percent_of_archive"`
+	PaddingOK  bool    `json:"padding_ok"`
+}
+
+// InspectReport summarizes a VFS archive: its header, a per-entry report,
+// and any structural problems found while validating it. It is built
+// without writing anything to disk.
+type InspectReport struct {
+	Version     string        `json:"version"`
+	FileCount   uint32        `json:"file_count"`
+	ArchiveSize int64         `json:"archive_size"`
+	Entries     []EntryReport `json:"entries"`
+	Problems    []string      `json:"problems,omitempty"`
+}
+
+// buildInspectReport walks an archive's table of contents and verifies the
+// structural invariants extractFiles and Archive's fs.FS view rely on:
+// non-empty, non-colliding names, data ranges that fit inside the archive,
+// non-overlapping data regions, and a padding suffix that is either all
+// zero or at least consistent across entries. archive is typically opened
+// with NewArchiveForInspect, since these are exactly the invariants
+// NewArchive itself would otherwise refuse to open.
+func buildInspectReport(archive *Archive) *InspectReport {
+	entries := archive.Entries()
+	archiveSize := archive.Size()
+
+	report := &InspectReport{
+		Version:     fmt.Sprintf("%v", archive.Header.Version[:]),
+		FileCount:   archive.Header.FileCount,
+		ArchiveSize: archiveSize,
+	}
+
+	seenNames := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		if len(entry.Name) == 0 {
+			report.Problems = append(report.Problems, fmt.Sprintf("entry %d: empty name", i+1))
+			continue
+		}
+		if !fs.ValidPath(entry.Name) {
+			report.Problems = append(report.Problems, fmt.Sprintf("entry %d: invalid archive path '%s'", i+1, entry.Name))
+		}
+		if first, exists := seenNames[entry.Name]; exists {
+			report.Problems = append(report.Problems, fmt.Sprintf("entry %d: duplicate archive path '%s' (first seen at entry %d)", i+1, entry.Name, first))
+		} else {
+			seenNames[entry.Name] = i + 1
+		}
+	}
+
+	byOffset := make([]VFSEntryMetadata, len(entries))
+	copy(byOffset, entries)
+	sort.Slice(byOffset, func(i, j int) bool { return byOffset[i].FileOffset < byOffset[j].FileOffset })
+
+	for i, entry := range byOffset {
+		end := uint64(entry.FileOffset) + uint64(entry.FileSize)
+		if end > uint64(archiveSize) {
+			report.Problems = append(report.Problems, fmt.Sprintf(
+				"entry '%s': data range [%d, %d) exceeds archive size %d", entry.Name, entry.FileOffset, end, archiveSize))
+		}
+
+		if i > 0 {
+			prev := byOffset[i-1]
+			prevEnd := uint64(prev.FileOffset) + uint64(prev.FileSize)
+			if uint64(entry.FileOffset) < prevEnd {
+				report.Problems = append(report.Problems, fmt.Sprintf(
+					"entry '%s' [%d, %d) overlaps preceding entry '%s' [%d, %d)",
+					entry.Name, entry.FileOffset, end, prev.Name, prev.FileOffset, prevEnd))
+			}
+		}
+	}
+
+	paddingCounts := make(map[[8]byte]int)
+	for _, entry := range entries {
+		paddingCounts[entry.padding]++
+	}
+	var nonZeroPaddings [][8]byte
+	for pad := range paddingCounts {
+		if pad != ([8]byte{}) {
+			nonZeroPaddings = append(nonZeroPaddings, pad)
+		}
+	}
+	sort.Slice(nonZeroPaddings, func(i, j int) bool {
+		return fmt.Sprintf("%x", nonZeroPaddings[i]) < fmt.Sprintf("%x", nonZeroPaddings[j])
+	})
+	for _, pad := range nonZeroPaddings {
+		report.Problems = append(report.Problems, fmt.Sprintf(
+			"padding suffix %x seen in %d entr(y/ies) (expected all-zero)", pad, paddingCounts[pad]))
+	}
+
+	for _, entry := range entries {
+		percent := 0.0
+		if archiveSize > 0 {
+			percent = float64(entry.FileSize) / float64(archiveSize) * 100
+		}
+		report.Entries = append(report.Entries, EntryReport{
+			Name:       entry.Name,
+			FileOffset: entry.FileOffset,
+			FileSize:   entry.FileSize,
+			Percent:    percent,
+			PaddingOK:  entry.padding == [8]byte{},
+		})
+	}
+
+	return report
+}
+
+// printInspectReport prints a human-readable rendering of report to stdout.
+func printInspectReport(report *InspectReport) {
+	fmt.Printf("VFS format version: %s\n", report.Version)
+	fmt.Printf("Archive size: %d bytes\n", report.ArchiveSize)
+	fmt.Printf("File count: %d\n\n", report.FileCount)
+
+	fmt.Printf("%-48s %12s %12s %8s\n", "Name", "Offset", "Size", "% Arch")
+	for _, e := range report.Entries {
+		fmt.Printf("%-48s %12d %12d %7.2f%%\n", e.Name, e.FileOffset, e.FileSize, e.Percent)
+	}
+
+	if len(report.Problems) == 0 {
+		fmt.Println("\nNo structural problems found.")
+		return
+	}
+
+	fmt.Printf("\n%d problem(s) found:\n", len(report.Problems))
+	for _, p := range report.Problems {
+		fmt.Printf("  - %s\n", p)
+	}
+}
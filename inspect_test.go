@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildInspectReportCleanArchive(t *testing.T) {
+	archive, original := buildSampleArchive(t)
+
+	report := buildInspectReport(archive)
+
+	if len(report.Problems) != 0 {
+		t.Fatalf("Problems = %v, want none", report.Problems)
+	}
+	if len(report.Entries) != len(original) {
+		t.Fatalf("len(Entries) = %d, want %d", len(report.Entries), len(original))
+	}
+	for _, e := range report.Entries {
+		if !e.PaddingOK {
+			t.Fatalf("entry %q: PaddingOK = false, want true for a freshly packed archive", e.Name)
+		}
+	}
+}
+
+func TestBuildInspectReportDetectsOverlap(t *testing.T) {
+	srcDir := t.TempDir()
+	buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	archive, err := NewArchive(archivePath)
+	if err != nil {
+		t.Fatalf("NewArchive failed: %v", err)
+	}
+	defer archive.Close()
+
+	// Corrupt the in-memory table of contents to simulate an archive
+	// whose entries overlap on disk.
+	for i := range archive.entries {
+		archive.entries[i].FileOffset = 0
+	}
+
+	report := buildInspectReport(archive)
+
+	if len(report.Problems) == 0 {
+		t.Fatalf("Problems = %v, want at least one overlap report", report.Problems)
+	}
+}
+
+// writeHandCraftedArchive writes a minimal VFS file with two entries: one
+// whose data range runs past the end of the archive, and one that
+// duplicates the other's path - both invariants NewArchive rejects outright.
+func writeHandCraftedArchive(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.Write(vfsMagicBytes)
+	buf.Write(supportedVFSVersion)
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+
+	writeEntry := func(name string, fileSize, fileOffset uint32) {
+		buf.WriteByte(byte(len(name)))
+		buf.WriteString(name)
+		binary.Write(&buf, binary.LittleEndian, fileSize)
+		binary.Write(&buf, binary.LittleEndian, fileOffset)
+		buf.Write(make([]byte, 8)) // padding
+	}
+	writeEntry("readme.txt", 1000, 0) // offset 0 + size 1000 exceeds archive size
+	writeEntry("readme.txt", 0, 0)    // duplicate path
+
+	archivePath := filepath.Join(t.TempDir(), "corrupt.vfs")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return archivePath
+}
+
+func TestNewArchiveRejectsOutOfRangeEntry(t *testing.T) {
+	archivePath := writeHandCraftedArchive(t)
+
+	if _, err := NewArchive(archivePath); err == nil {
+		t.Fatal("NewArchive succeeded, want an error for an out-of-range entry")
+	}
+}
+
+// TestNewArchiveForInspectSurfacesProblems covers the inspect CLI's whole
+// point: a file NewArchive refuses to open because of an out-of-range
+// entry or a duplicate path must still open for inspection, with both
+// conditions reported as InspectReport problems rather than a fatal error.
+func TestNewArchiveForInspectSurfacesProblems(t *testing.T) {
+	archivePath := writeHandCraftedArchive(t)
+
+	archive, err := NewArchiveForInspect(archivePath)
+	if err != nil {
+		t.Fatalf("NewArchiveForInspect failed: %v", err)
+	}
+	defer archive.Close()
+
+	report := buildInspectReport(archive)
+	if len(report.Entries) != 2 {
+		t.Fatalf("len(Entries) = %d, want 2", len(report.Entries))
+	}
+
+	var sawRangeProblem, sawDuplicateProblem bool
+	for _, p := range report.Problems {
+		if strings.Contains(p, "exceeds archive size") {
+			sawRangeProblem = true
+		}
+		if strings.Contains(p, "duplicate archive path") {
+			sawDuplicateProblem = true
+		}
+	}
+	if !sawRangeProblem {
+		t.Fatalf("Problems = %v, want an out-of-range data range problem", report.Problems)
+	}
+	if !sawDuplicateProblem {
+		t.Fatalf("Problems = %v, want a duplicate archive path problem", report.Problems)
+	}
+}
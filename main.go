@@ -1,24 +1,61 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// stringListFlag accumulates the values of a repeatable string flag, such
+// as `--include a --include b`.
+type stringListFlag []string
+
+func (l *stringListFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *stringListFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
-	// Handle command-line arguments
-	if len(os.Args) < 2 || len(os.Args) > 3 {
+	if len(os.Args) >= 2 {
+		switch os.Args[1] {
+		case "pack":
+			runPack(os.Args[2:])
+			return
+		case "inspect", "list":
+			runInspect(os.Args[2:])
+			return
+		}
+	}
+
+	runUnpack(os.Args[1:])
+}
+
+// runUnpack handles the default
+// `[-j N] [--include glob]... [--exclude glob]... [--dry-run] <vfs> [output_directory]` mode.
+func runUnpack(args []string) {
+	fs := flag.NewFlagSet("unpack", flag.ExitOnError)
+	workers := fs.Int("j", 1, "number of files to extract concurrently")
+	dryRun := fs.Bool("dry-run", false, "print what would be extracted, without writing any files")
+	var includes, excludes stringListFlag
+	fs.Var(&includes, "include", "glob pattern to extract (repeatable); matches the archive's normalized entry name")
+	fs.Var(&excludes, "exclude", "glob pattern to skip (repeatable); takes precedence over --include")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	vfsPath := filepath.Clean(os.Args[1])
+	vfsPath := filepath.Clean(fs.Arg(0))
 	outputDir := ""
 
-	if len(os.Args) == 3 {
-		outputDir = filepath.Clean(os.Args[2])
+	if fs.NArg() == 2 {
+		outputDir = filepath.Clean(fs.Arg(1))
 	} else {
 		// Default output directory name: VFS filename without extension
 		baseName := filepath.Base(vfsPath)
@@ -39,6 +76,17 @@ func main() {
 	// Ensure VFS file is closed on main exit
 	defer unpacker.Close()
 
+	unpacker.Workers = *workers
+	unpacker.DryRun = *dryRun
+	if len(includes) > 0 || len(excludes) > 0 {
+		filter, err := NewGlobFilter(includes, excludes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nInitialization error: %v\n", err)
+			os.Exit(1)
+		}
+		unpacker.SetFilter(filter)
+	}
+
 	// Start unpacking
 	err = unpacker.Unpack()
 	if err != nil {
@@ -48,14 +96,89 @@ func main() {
 	}
 }
 
+// runPack handles the `pack <dir> <out.vfs>` mode.
+func runPack(args []string) {
+	if len(args) != 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	inputDir := filepath.Clean(args[0])
+	outPath := filepath.Clean(args[1])
+
+	fmt.Printf("Input Directory: %s\n", inputDir)
+	fmt.Printf("Output VFS: %s\n", outPath)
+
+	packer, err := NewPacker(inputDir, outPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nInitialization error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := packer.Pack(); err != nil {
+		fmt.Fprintf(os.Stderr, "\nError during packing: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runInspect handles the `inspect <vfs> [-json]` mode (aliased as `list`).
+// It prints the header and table of contents without writing anything to
+// disk.
+func runInspect(args []string) {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "print the report as JSON instead of a human-readable table")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	vfsPath := filepath.Clean(fs.Arg(0))
+
+	archive, err := NewArchiveForInspect(vfsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nInitialization error: %v\n", err)
+		os.Exit(1)
+	}
+	defer archive.Close()
+
+	report := buildInspectReport(archive)
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "\nError encoding report: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	printInspectReport(report)
+}
+
 // printUsage prints program usage information.
 func printUsage() {
 	appName := filepath.Base(os.Args[0])
 
-	fmt.Printf("Usage: %s <path_to_vfs_file> [output_directory]\n", appName)
+	fmt.Printf("Usage: %s [-j N] [--include glob]... [--exclude glob]... [--dry-run] <path_to_vfs_file> [output_directory]\n", appName)
 	fmt.Println("If output_directory is not specified, a directory named after")
 	fmt.Println("the VFS file (without extension) in the current location is used.")
+	fmt.Println("-j N extracts up to N files concurrently (default 1).")
+	fmt.Println("--include/--exclude filter entries by glob, matched against their normalized")
+	fmt.Println("archive path; --exclude wins on conflicts. --dry-run lists what would be")
+	fmt.Println("extracted, and the total size, without writing anything.")
+	fmt.Printf("       %s pack <input_directory> <path_to_vfs_file>\n", appName)
+	fmt.Println("Builds a new VFS archive from the contents of input_directory.")
+	fmt.Printf("       %s inspect [-json] <path_to_vfs_file>\n", appName)
+	fmt.Println("Lists the archive's contents and checks it for structural problems,")
+	fmt.Println("without writing anything to disk. `list` is an alias for `inspect`.")
 	fmt.Println("\nExamples:")
 	fmt.Printf("  %s \"D:\\Steam\\steamapps\\common\\Pathologic Classic HD\\data\\Sounds.vfs\"\n", appName) // Example path
 	fmt.Printf("  %s Sounds.vfs extracted_sounds\n", appName)
+	fmt.Printf("  %s -j 8 Sounds.vfs extracted_sounds\n", appName)
+	fmt.Printf("  %s --include 'music/*.ogg' --dry-run Sounds.vfs\n", appName)
+	fmt.Printf("  %s pack extracted_sounds Sounds.vfs\n", appName)
+	fmt.Printf("  %s inspect -json Sounds.vfs\n", appName)
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packEntry describes a single file discovered while walking the input
+// directory, before its final offset in the archive is known.
+type packEntry struct {
+	// name is the entry name as it will be stored in the archive, i.e.
+	// with the platform path separator swapped for the backslash the
+	// VFS format expects.
+	name     string
+	diskPath string
+	size     int64
+}
+
+// Packer encapsulates the state and logic for building a VFS archive from
+// a directory tree.
+type Packer struct {
+	inputDir string
+	outPath  string
+}
+
+// NewPacker creates a new Packer instance that will archive the contents of
+// inputDir into outPath.
+func NewPacker(inputDir string, outPath string) (*Packer, error) {
+	info, err := os.Stat(inputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat input directory '%s': %w", inputDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("input path '%s' is not a directory", inputDir)
+	}
+
+	return &Packer{
+		inputDir: inputDir,
+		outPath:  outPath,
+	}, nil
+}
+
+// Pack walks the input directory and writes a complete LP1C archive to the
+// configured output path.
+func (p *Packer) Pack() error {
+	entries, err := p.collectEntries()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Packing %d file(s) from '%s' into '%s'\n", len(entries), p.inputDir, p.outPath)
+
+	if len(entries) > math.MaxUint32 {
+		return fmt.Errorf("too many files to pack (%d): file count must fit in a uint32", len(entries))
+	}
+
+	outFile, err := os.Create(p.outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output archive '%s': %w", p.outPath, err)
+	}
+	defer outFile.Close()
+
+	if err := writeHeader(outFile, uint32(len(entries))); err != nil {
+		return err
+	}
+
+	// Metadata is written before any file data, so offsets into the data
+	// region must be computed up front: the data region begins right
+	// after the last entry's metadata block.
+	metadataSize := int64(0)
+	for _, e := range entries {
+		metadataSize += 1 + int64(len(e.name)) + entryFixedMetadataSuffixSize
+	}
+	dataStart := vfsHeaderSize + metadataSize
+
+	offset := dataStart
+	for i, e := range entries {
+		if e.size > math.MaxUint32 {
+			return fmt.Errorf("entry '%s': file too large to pack (%d bytes exceeds uint32 range)", e.name, e.size)
+		}
+		if offset > math.MaxUint32 {
+			return fmt.Errorf("entry '%s': archive too large to pack (offset %d exceeds uint32 range)", e.name, offset)
+		}
+		if err := writeEntryMetadata(outFile, e.name, uint32(e.size), uint32(offset)); err != nil {
+			return fmt.Errorf("entry %d ('%s'): failed to write metadata: %w", i+1, e.name, err)
+		}
+		offset += e.size
+	}
+
+	for i, e := range entries {
+		if err := appendEntryData(outFile, e); err != nil {
+			return fmt.Errorf("entry %d ('%s'): failed to write file data: %w", i+1, e.name, err)
+		}
+		fmt.Printf("Packed (%d/%d): %s (%d bytes)\n", i+1, len(entries), e.name, e.size)
+	}
+
+	fmt.Println("Packing finished successfully.")
+	return nil
+}
+
+// collectEntries walks the input directory and returns one packEntry per
+// regular file, sorted by archive name so packing is deterministic.
+func (p *Packer) collectEntries() ([]packEntry, error) {
+	var entries []packEntry
+
+	err := filepath.WalkDir(p.inputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk '%s': %w", path, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(p.inputDir, path)
+		if err != nil {
+			return fmt.Errorf("failed to compute relative path for '%s': %w", path, err)
+		}
+		// The VFS format stores names with backslash separators
+		// regardless of host OS, matching the unpacker's normalization.
+		name := strings.ReplaceAll(relPath, string(filepath.Separator), "\\")
+		if len(name) == 0 || len(name) > math.MaxUint8 {
+			return fmt.Errorf("entry name '%s' has invalid length %d (must be 1-%d bytes)", name, len(name), math.MaxUint8)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat '%s': %w", path, err)
+		}
+
+		entries = append(entries, packEntry{
+			name:     name,
+			diskPath: path,
+			size:     info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	return entries, nil
+}
+
+// writeHeader writes the 12-byte LP1C header: magic, version, file count.
+func writeHeader(w io.Writer, fileCount uint32) error {
+	if _, err := w.Write(vfsMagicBytes); err != nil {
+		return fmt.Errorf("failed to write magic bytes: %w", err)
+	}
+	if _, err := w.Write(supportedVFSVersion); err != nil {
+		return fmt.Errorf("failed to write version bytes: %w", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, fileCount); err != nil {
+		return fmt.Errorf("failed to write file count: %w", err)
+	}
+	return nil
+}
+
+// writeEntryMetadata writes one entry's table-of-contents record: name
+// length, name, file size, file offset, and the 8-byte padding suffix.
+func writeEntryMetadata(w io.Writer, name string, fileSize uint32, fileOffset uint32) error {
+	if _, err := w.Write([]byte{uint8(len(name))}); err != nil {
+		return fmt.Errorf("failed to write name length: %w", err)
+	}
+	if _, err := io.WriteString(w, name); err != nil {
+		return fmt.Errorf("failed to write name: %w", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, fileSize); err != nil {
+		return fmt.Errorf("failed to write file size: %w", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, fileOffset); err != nil {
+		return fmt.Errorf("failed to write file offset: %w", err)
+	}
+	padding := make([]byte, entryFixedMetadataSuffixSize-4-4)
+	if _, err := w.Write(padding); err != nil {
+		return fmt.Errorf("failed to write metadata padding: %w", err)
+	}
+	return nil
+}
+
+// appendEntryData copies one file's contents from disk onto the end of the
+// archive being written.
+func appendEntryData(w io.Writer, e packEntry) error {
+	f, err := os.Open(e.diskPath)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", e.diskPath, err)
+	}
+	defer f.Close()
+
+	written, err := io.Copy(w, f)
+	if err != nil {
+		return fmt.Errorf("failed to copy data from '%s': %w", e.diskPath, err)
+	}
+	if written != e.size {
+		return fmt.Errorf("'%s' changed size while packing (expected %d bytes, wrote %d)", e.diskPath, e.size, written)
+	}
+	return nil
+}
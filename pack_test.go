@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// buildSampleTree creates a small nested directory tree with a few files
+// of varying size for use as packer/unpacker fixtures.
+func buildSampleTree(t *testing.T, root string) map[string][]byte {
+	t.Helper()
+
+	files := map[string][]byte{
+		"readme.txt":                     []byte("hello, world"),
+		filepath.Join("a", "b.dat"):      make([]byte, 1024),
+		filepath.Join("a", "c", "d.bin"): []byte{0x00, 0x01, 0x02, 0x03},
+	}
+	for i := range files[filepath.Join("a", "b.dat")] {
+		files[filepath.Join("a", "b.dat")][i] = byte(i % 251)
+	}
+
+	for rel, data := range files {
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("failed to create directory for '%s': %v", full, err)
+		}
+		if err := os.WriteFile(full, data, 0644); err != nil {
+			t.Fatalf("failed to write fixture file '%s': %v", full, err)
+		}
+	}
+
+	return files
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	original := buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "extracted")
+	unpacker, err := NewUnpacker(archivePath, outDir)
+	if err != nil {
+		t.Fatalf("NewUnpacker failed: %v", err)
+	}
+	defer unpacker.Close()
+	if err := unpacker.Unpack(); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+
+	var gotFiles []string
+	err = filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(outDir, path)
+		if err != nil {
+			return err
+		}
+		gotFiles = append(gotFiles, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk extracted output: %v", err)
+	}
+
+	var wantFiles []string
+	for rel := range original {
+		wantFiles = append(wantFiles, rel)
+	}
+	sort.Strings(gotFiles)
+	sort.Strings(wantFiles)
+	if !reflect.DeepEqual(gotFiles, wantFiles) {
+		t.Fatalf("extracted file set = %v, want %v", gotFiles, wantFiles)
+	}
+
+	for rel, want := range original {
+		got, err := os.ReadFile(filepath.Join(outDir, rel))
+		if err != nil {
+			t.Fatalf("failed to read extracted file '%s': %v", rel, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("extracted file '%s' does not match original (got %d bytes, want %d bytes)", rel, len(got), len(want))
+		}
+	}
+}
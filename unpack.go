@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// extractBufferPool holds reusable copy buffers so concurrent extraction
+// doesn't allocate a fresh buffer (or materialize a whole entry) per file.
+var extractBufferPool = sync.Pool{
+	New: func() any { return make([]byte, 32*1024) },
+}
+
+// Unpacker extracts every entry of a VFS archive to disk. It is a thin
+// client over Archive: all parsing and random access lives there, this
+// type just walks the entries and copies each one out.
+type Unpacker struct {
+	archive   *Archive
+	outputDir string
+
+	// Workers is the number of files extracted concurrently. Values less
+	// than 1 are treated as 1.
+	Workers int
+
+	// DryRun, when true, makes Unpack print what would be extracted
+	// (and the total byte count) without creating the output directory
+	// or any files.
+	DryRun bool
+
+	filter func(VFSEntryMetadata) bool
+}
+
+// SetFilter restricts extraction to entries for which f returns true.
+// Filtered-out entries are still consumed while walking the archive, but
+// nothing is written to disk for them. A nil filter (the default) extracts
+// everything.
+func (u *Unpacker) SetFilter(f func(VFSEntryMetadata) bool) {
+	u.filter = f
+}
+
+// NewGlobFilter builds an Unpacker filter from repeatable include/exclude
+// glob patterns, matched against an entry's normalized (forward-slash)
+// archive path with path.Match. An entry passes if it matches no exclude
+// pattern and either matches an include pattern or no include patterns
+// were given. Exclude takes precedence over include. It returns an error
+// if any pattern is malformed, rather than letting it silently fail to
+// match anything.
+func NewGlobFilter(includes, excludes []string) (func(VFSEntryMetadata) bool, error) {
+	for _, pattern := range includes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --include pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range excludes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	return func(entry VFSEntryMetadata) bool {
+		for _, pattern := range excludes {
+			if ok, _ := path.Match(pattern, entry.Name); ok {
+				return false
+			}
+		}
+		if len(includes) == 0 {
+			return true
+		}
+		for _, pattern := range includes {
+			if ok, _ := path.Match(pattern, entry.Name); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// NewUnpacker creates a new Unpacker instance.
+func NewUnpacker(vfsPath string, outputDir string) (*Unpacker, error) {
+	archive, err := NewArchive(vfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Detected VFS format version: %v (Supported)\n", archive.Header.Version[:])
+	fmt.Printf("Archive contains %d files.\n", archive.Header.FileCount)
+
+	return &Unpacker{
+		archive:   archive,
+		outputDir: outputDir,
+		Workers:   1,
+	}, nil
+}
+
+// Close closes the underlying VFS file.
+func (u *Unpacker) Close() error {
+	return u.archive.Close()
+}
+
+// createOutputDirectory creates the base output directory.
+func (u *Unpacker) createOutputDirectory() error {
+	fmt.Printf("Creating output directory: %s\n", u.outputDir)
+	if err := os.MkdirAll(u.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create base output directory '%s': %w", u.outputDir, err)
+	}
+	return nil
+}
+
+// extractFiles copies every archive entry out to the output directory,
+// running up to u.Workers extractions concurrently. The first worker
+// error cancels the remaining, not-yet-started work; all errors observed
+// before that point are joined together.
+func (u *Unpacker) extractFiles() error {
+	entries := u.archive.Entries()
+	total := len(entries)
+	if total == 0 {
+		return nil
+	}
+
+	workers := u.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > total {
+		workers = total
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indices := make(chan int)
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			buf := extractBufferPool.Get().([]byte)
+			defer extractBufferPool.Put(buf)
+
+			for idx := range indices {
+				entry := entries[idx]
+				if u.filter != nil && !u.filter(entry) {
+					fmt.Printf("Skipped (%d/%d): %s (filtered out)\n", idx+1, total, entry.Name)
+					continue
+				}
+				if err := u.extractSingleFile(entry, uint32(idx+1), uint32(total), buf); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range entries {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+	close(errs)
+
+	var joined []error
+	for err := range errs {
+		joined = append(joined, err)
+	}
+	return errors.Join(joined...)
+}
+
+// extractSingleFile extracts the data of a single file based on its
+// metadata, streaming it straight into the output file via buf without
+// ever materializing the whole entry in memory.
+func (u *Unpacker) extractSingleFile(entry VFSEntryMetadata, entryIndex uint32, total uint32, buf []byte) error {
+	src, err := u.archive.Open(entry.Name)
+	if err != nil {
+		return fmt.Errorf("entry %d ('%s'): failed to open from archive: %w", entryIndex, entry.Name, err)
+	}
+	defer src.Close()
+
+	outputFilePath := filepath.Join(u.outputDir, filepath.FromSlash(entry.Name))
+
+	// Ensure the directory for the file exists
+	if err := os.MkdirAll(filepath.Dir(outputFilePath), 0755); err != nil {
+		return fmt.Errorf("entry %d ('%s'): failed to create output directory '%s': %w", entryIndex, entry.Name, filepath.Dir(outputFilePath), err)
+	}
+
+	// Create and write the file
+	outFile, err := os.Create(outputFilePath)
+	if err != nil {
+		return fmt.Errorf("entry %d ('%s'): failed to create output file '%s': %w", entryIndex, entry.Name, outputFilePath, err)
+	}
+
+	if _, err := io.CopyBuffer(outFile, src, buf); err != nil {
+		// Attempt to remove partially written file on error
+		outFile.Close()
+		os.Remove(outputFilePath)
+		return fmt.Errorf("entry %d ('%s'): failed to write data to '%s': %w", entryIndex, entry.Name, outputFilePath, err)
+	}
+
+	// Close the file explicitly here to check the close error
+	if closeErr := outFile.Close(); closeErr != nil {
+		// Close error is less critical, but worth reporting
+		fmt.Fprintf(os.Stderr, "Warning: failed to close output file '%s': %v\n", outputFilePath, closeErr)
+	}
+
+	fmt.Printf("Extracted (%d/%d): %s (%d bytes)\n", entryIndex, total, entry.Name, entry.FileSize)
+
+	return nil
+}
+
+// printDryRun reports what Unpack would extract without writing anything
+// to disk.
+func (u *Unpacker) printDryRun() {
+	entries := u.archive.Entries()
+
+	var matched int
+	var totalBytes uint64
+	for _, entry := range entries {
+		if u.filter != nil && !u.filter(entry) {
+			continue
+		}
+		fmt.Printf("Would extract: %s (%d bytes)\n", entry.Name, entry.FileSize)
+		matched++
+		totalBytes += uint64(entry.FileSize)
+	}
+
+	fmt.Printf("Dry run: %d of %d file(s) would be extracted, %d byte(s) total.\n", matched, len(entries), totalBytes)
+}
+
+// Unpack performs the complete unpacking process.
+func (u *Unpacker) Unpack() error {
+	if u.archive.Header.FileCount == 0 {
+		fmt.Println("No files to extract.")
+		return nil
+	}
+
+	if u.DryRun {
+		u.printDryRun()
+		return nil
+	}
+
+	if err := u.createOutputDirectory(); err != nil {
+		return err
+	}
+
+	if err := u.extractFiles(); err != nil {
+		return err
+	}
+
+	fmt.Println("Unpacking finished successfully.")
+	return nil
+}
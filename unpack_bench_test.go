@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// defaultBenchFileCount and defaultBenchFileSizeMB size the benchmark
+// archive built by buildBenchArchive. Override them with the
+// BENCH_FILE_COUNT and BENCH_FILE_SIZE_MB environment variables (go test
+// flags can't take extra parameters) to approximate a multi-GB archive,
+// e.g. BENCH_FILE_COUNT=8 BENCH_FILE_SIZE_MB=512 go test -bench Extract.
+const (
+	defaultBenchFileCount  = 8
+	defaultBenchFileSizeMB = 4
+)
+
+func benchEnvInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// buildBenchArchive packs a handful of multi-megabyte files, simulating the
+// large sound/video blobs a real Sounds.vfs might contain, and returns the
+// path to the resulting archive.
+func buildBenchArchive(b *testing.B, fileCount int, fileSize int) string {
+	b.Helper()
+
+	srcDir := b.TempDir()
+	data := make([]byte, fileSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for i := 0; i < fileCount; i++ {
+		name := filepath.Join(srcDir, "file"+string(rune('a'+i))+".bin")
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			b.Fatalf("failed to write fixture file '%s': %v", name, err)
+		}
+	}
+
+	archivePath := filepath.Join(b.TempDir(), "bench.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		b.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		b.Fatalf("Pack failed: %v", err)
+	}
+
+	return archivePath
+}
+
+func runExtractBenchmark(b *testing.B, workers int) {
+	fileCount := benchEnvInt("BENCH_FILE_COUNT", defaultBenchFileCount)
+	fileSizeMB := benchEnvInt("BENCH_FILE_SIZE_MB", defaultBenchFileSizeMB)
+	archivePath := buildBenchArchive(b, fileCount, fileSizeMB<<20)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		outDir := filepath.Join(b.TempDir(), "out")
+		unpacker, err := NewUnpacker(archivePath, outDir)
+		if err != nil {
+			b.Fatalf("NewUnpacker failed: %v", err)
+		}
+		unpacker.Workers = workers
+		b.StartTimer()
+
+		if err := unpacker.Unpack(); err != nil {
+			b.Fatalf("Unpack failed: %v", err)
+		}
+
+		b.StopTimer()
+		unpacker.Close()
+		b.StartTimer()
+	}
+}
+
+// BenchmarkExtractSingleThreaded extracts with a single worker, matching
+// the tool's original behavior.
+func BenchmarkExtractSingleThreaded(b *testing.B) {
+	runExtractBenchmark(b, 1)
+}
+
+// BenchmarkExtractMultiThreaded extracts with one worker per CPU, to
+// compare against BenchmarkExtractSingleThreaded on the same archive.
+func BenchmarkExtractMultiThreaded(b *testing.B) {
+	runExtractBenchmark(b, runtime.NumCPU())
+}
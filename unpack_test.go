@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnpackWithWorkersMatchesSingleThreaded(t *testing.T) {
+	srcDir := t.TempDir()
+	original := buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "extracted")
+	unpacker, err := NewUnpacker(archivePath, outDir)
+	if err != nil {
+		t.Fatalf("NewUnpacker failed: %v", err)
+	}
+	defer unpacker.Close()
+	unpacker.Workers = 4
+
+	if err := unpacker.Unpack(); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+
+	for rel, want := range original {
+		got, err := os.ReadFile(filepath.Join(outDir, rel))
+		if err != nil {
+			t.Fatalf("failed to read extracted file '%s': %v", rel, err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("extracted file '%s' does not match original (got %d bytes, want %d bytes)", rel, len(got), len(want))
+		}
+	}
+}
+
+func TestUnpackWithWorkersAggregatesPerFileErrors(t *testing.T) {
+	srcDir := t.TempDir()
+	buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	// Pre-create the output directory with a regular file where the
+	// archive expects a subdirectory ("a/b.dat", "a/c/d.bin"), so every
+	// worker that reaches one of those entries fails.
+	outDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outDir, "a"), []byte("blocks the 'a' directory"), 0644); err != nil {
+		t.Fatalf("failed to create blocking file: %v", err)
+	}
+
+	unpacker, err := NewUnpacker(archivePath, outDir)
+	if err != nil {
+		t.Fatalf("NewUnpacker failed: %v", err)
+	}
+	defer unpacker.Close()
+	unpacker.Workers = 4
+
+	if err := unpacker.Unpack(); err == nil {
+		t.Fatal("Unpack succeeded, want error because 'a' collides with a required subdirectory")
+	}
+}
+
+func TestUnpackWithFilterOnlyWritesMatchedEntries(t *testing.T) {
+	srcDir := t.TempDir()
+	buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "extracted")
+	unpacker, err := NewUnpacker(archivePath, outDir)
+	if err != nil {
+		t.Fatalf("NewUnpacker failed: %v", err)
+	}
+	defer unpacker.Close()
+	filter, err := NewGlobFilter([]string{"readme.txt"}, nil)
+	if err != nil {
+		t.Fatalf("NewGlobFilter failed: %v", err)
+	}
+	unpacker.SetFilter(filter)
+
+	if err := unpacker.Unpack(); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "readme.txt")); err != nil {
+		t.Fatalf("expected readme.txt to be extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "a")); !os.IsNotExist(err) {
+		t.Fatalf("expected 'a' to be skipped by the filter, got err = %v", err)
+	}
+}
+
+func TestUnpackDryRunWritesNothing(t *testing.T) {
+	srcDir := t.TempDir()
+	buildSampleTree(t, srcDir)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.vfs")
+	packer, err := NewPacker(srcDir, archivePath)
+	if err != nil {
+		t.Fatalf("NewPacker failed: %v", err)
+	}
+	if err := packer.Pack(); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "extracted")
+	unpacker, err := NewUnpacker(archivePath, outDir)
+	if err != nil {
+		t.Fatalf("NewUnpacker failed: %v", err)
+	}
+	defer unpacker.Close()
+	unpacker.DryRun = true
+
+	if err := unpacker.Unpack(); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not create the output directory, got err = %v", err)
+	}
+}
+
+func TestNewGlobFilterRejectsMalformedPattern(t *testing.T) {
+	if _, err := NewGlobFilter([]string{"[unterminated"}, nil); err == nil {
+		t.Fatal("NewGlobFilter succeeded, want error for a malformed --include pattern")
+	}
+	if _, err := NewGlobFilter(nil, []string{"[unterminated"}); err == nil {
+		t.Fatal("NewGlobFilter succeeded, want error for a malformed --exclude pattern")
+	}
+}